@@ -0,0 +1,16 @@
+// Package cache provides a small disk-backed cache used to memoize GitLab
+// API responses across runs, so re-running the aggregator over a long
+// history doesn't re-fetch commits that were already scored.
+package cache
+
+// Cache is a byte-oriented key/value store.
+type Cache interface {
+	Get(key []byte) ([]byte, bool)
+	// Put stores value under key, subject to the implementation's configured
+	// TTL (if any).
+	Put(key []byte, value []byte) error
+	// PutForever stores value under key so it never expires, regardless of
+	// the implementation's configured TTL. Use this for entries keyed by
+	// something immutable, like a commit SHA.
+	PutForever(key []byte, value []byte) error
+}
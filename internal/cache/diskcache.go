@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// entry is the on-disk envelope for a cached value. A zero TTL means the
+// entry never expires, which is what we want for responses keyed by an
+// immutable commit SHA.
+type entry struct {
+	Value    []byte        `json:"value"`
+	StoredAt time.Time     `json:"stored_at"`
+	TTL      time.Duration `json:"ttl"`
+}
+
+func (e entry) expired(now time.Time) bool {
+	return e.TTL > 0 && now.Sub(e.StoredAt) > e.TTL
+}
+
+// DiskCache is a Cache backed by a sharded directory of JSON files: entries
+// are spread across 256 subdirectories keyed by the first byte of the
+// SHA-256 of their key, to keep any one directory from growing huge on
+// instances with a long commit history.
+type DiskCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// NewDiskCache creates (if needed) dir and returns a DiskCache that stores
+// entries there, expiring them after ttl (0 means entries never expire).
+func NewDiskCache(dir string, ttl time.Duration) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	return &DiskCache{dir: dir, ttl: ttl}, nil
+}
+
+func (c *DiskCache) Get(key []byte) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	if e.expired(time.Now()) {
+		return nil, false
+	}
+
+	return e.Value, true
+}
+
+func (c *DiskCache) Put(key []byte, value []byte) error {
+	return c.put(key, value, c.ttl)
+}
+
+func (c *DiskCache) PutForever(key []byte, value []byte) error {
+	return c.put(key, value, 0)
+}
+
+func (c *DiskCache) put(key []byte, value []byte, ttl time.Duration) error {
+	e := entry{Value: value, StoredAt: time.Now(), TTL: ttl}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache shard: %w", err)
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// path shards entries under dir/<2-hex-char-prefix>/<full-hex-digest>.json
+// so no single directory holds more than ~1/256th of the cache.
+func (c *DiskCache) path(key []byte) string {
+	sum := sha256.Sum256(key)
+	digest := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, digest[:2], digest+".json")
+}
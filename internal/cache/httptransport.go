@@ -0,0 +1,180 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// commitDetailPath matches a single-commit-detail endpoint, e.g. GitLab's
+// /api/v4/projects/123/repository/commits/<sha> or GitHub's
+// /repos/org/repo/commits/<sha>. Since SHAs are immutable, these responses
+// are cached forever under the SHA and never need a conditional re-request.
+var commitDetailPath = regexp.MustCompile(`/commits/([0-9a-f]{7,40})$`)
+
+// cachedResponse is what gets persisted in Cache for a GET request: the raw
+// response bytes plus the validators needed to make a conditional request
+// next time around.
+type cachedResponse struct {
+	Body         []byte `json:"body"`
+	ETag         string `json:"etag"`
+	LastModified string `json:"last_modified"`
+}
+
+// Transport wraps an http.RoundTripper with two caching strategies:
+//   - single-commit-detail responses are memoized forever under the commit
+//     SHA, since a SHA's diff never changes.
+//   - every other GET (notably paginated commit-list pages) is cached with
+//     its ETag/Last-Modified and re-validated with If-None-Match/
+//     If-Modified-Since, so an unchanged page costs a 304 instead of a full
+//     re-fetch.
+type Transport struct {
+	Base  http.RoundTripper
+	Cache Cache
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || t.Cache == nil {
+		return t.base().RoundTrip(req)
+	}
+
+	if sha := commitDetailPath.FindStringSubmatch(req.URL.Path); sha != nil {
+		return t.roundTripImmutable(req, []byte(sha[1]))
+	}
+	return t.roundTripConditional(req)
+}
+
+// roundTripImmutable serves straight from the cache keyed by SHA, falling
+// back to the network only on a miss.
+func (t *Transport) roundTripImmutable(req *http.Request, key []byte) (*http.Response, error) {
+	if raw, ok := t.Cache.Get(key); ok {
+		return t.synthesizeOK(req, raw), nil
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	_ = t.Cache.PutForever(key, body)
+	return resp, nil
+}
+
+// roundTripConditional caches the response under its URL and re-validates
+// with the previously seen ETag/Last-Modified.
+func (t *Transport) roundTripConditional(req *http.Request) (*http.Response, error) {
+	key := []byte(req.URL.String())
+	cached, hit := t.lookup(key)
+
+	if hit {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if hit && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		return t.synthesizeFrom(req, resp, cached.Body), nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		if etag := resp.Header.Get("ETag"); etag != "" || resp.Header.Get("Last-Modified") != "" {
+			_ = t.store(key, cachedResponse{
+				Body:         body,
+				ETag:         etag,
+				LastModified: resp.Header.Get("Last-Modified"),
+			})
+		}
+	}
+
+	return resp, nil
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) lookup(key []byte) (cachedResponse, bool) {
+	raw, ok := t.Cache.Get(key)
+	if !ok {
+		return cachedResponse{}, false
+	}
+	var cr cachedResponse
+	if err := json.Unmarshal(raw, &cr); err != nil {
+		return cachedResponse{}, false
+	}
+	return cr, true
+}
+
+func (t *Transport) store(key []byte, cr cachedResponse) error {
+	raw, err := json.Marshal(cr)
+	if err != nil {
+		return err
+	}
+	return t.Cache.Put(key, raw)
+}
+
+// synthesizeOK wraps a cached body in a 200 response, for cache hits that
+// never touched the network at all.
+func (t *Transport) synthesizeOK(req *http.Request, body []byte) *http.Response {
+	resp := &http.Response{
+		Status:     "200 OK (cached)",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return resp
+}
+
+// synthesizeFrom turns a 304 into a synthetic 200 carrying the previously
+// cached body.
+func (t *Transport) synthesizeFrom(req *http.Request, notModified *http.Response, body []byte) *http.Response {
+	resp := &http.Response{
+		Status:     "200 OK (cached)",
+		StatusCode: http.StatusOK,
+		Proto:      notModified.Proto,
+		ProtoMajor: notModified.ProtoMajor,
+		ProtoMinor: notModified.ProtoMinor,
+		Header:     notModified.Header.Clone(),
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	return resp
+}
@@ -0,0 +1,51 @@
+// Package forge abstracts over the different code-hosting backends this
+// tool can pull commit stats from, so the aggregation logic in main doesn't
+// need to know whether it's talking to GitLab, GitHub or Gerrit.
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/identity"
+)
+
+// Repo identifies a single repository on a forge.
+type Repo struct {
+	ID   string
+	Name string
+}
+
+// CommitRecord is a single commit's contribution, attributed to its raw
+// author and the day (formatted "2006-01-02") it was made. Callers group
+// these by (project, author, day) to build a report.
+type CommitRecord struct {
+	Author  identity.RawAuthor
+	Day     string
+	Added   int
+	Removed int
+	Total   int
+}
+
+// Forge lists repositories matching a pattern and pulls commit records for
+// a repository since a given date (formatted "2006-01-02").
+type Forge interface {
+	ListRepos(ctx context.Context, pattern string) ([]Repo, error)
+	CommitStats(ctx context.Context, repo Repo, since string) ([]CommitRecord, error)
+}
+
+// NewFromEnv builds the Forge selected by the FORGE env var
+// (gitlab|github|gerrit), defaulting to gitlab for backward compatibility.
+func NewFromEnv() (Forge, error) {
+	switch os.Getenv("FORGE") {
+	case "", "gitlab":
+		return NewGitLab()
+	case "github":
+		return NewGitHub()
+	case "gerrit":
+		return NewGerrit()
+	default:
+		return nil, fmt.Errorf("unknown FORGE %q: want gitlab, github or gerrit", os.Getenv("FORGE"))
+	}
+}
@@ -0,0 +1,174 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/httpclient"
+	"github.com/VielenDanke/gitlab-lines-collector/internal/identity"
+)
+
+// gerritXSSIPrefix is prepended by Gerrit to every JSON response body to
+// guard against cross-site script inclusion; it must be stripped before
+// decoding.
+const gerritXSSIPrefix = ")]}'"
+
+// gerritPageSize is the page size requested for both the projects and
+// changes endpoints, which Gerrit otherwise silently caps at its own
+// server-side default.
+const gerritPageSize = 500
+
+// gerritForge is the Forge implementation talking to Gerrit's REST API.
+type gerritForge struct {
+	baseURL string
+	user    string
+	token   string
+	client  *http.Client
+}
+
+// NewGerrit builds a Forge talking to Gerrit, reading GERRIT_URL, GERRIT_USER
+// and GERRIT_TOKEN (an HTTP password), and GERRIT_MAX_RETRIES/
+// GERRIT_RATE_LIMIT_QPS (see httpclient.New) from the environment.
+func NewGerrit() (Forge, error) {
+	baseURL := os.Getenv("GERRIT_URL")
+	if baseURL == "" {
+		return nil, fmt.Errorf("GERRIT_URL environment variable must be set")
+	}
+	user := os.Getenv("GERRIT_USER")
+	token := os.Getenv("GERRIT_TOKEN")
+	if user == "" || token == "" {
+		return nil, fmt.Errorf("GERRIT_USER and GERRIT_TOKEN environment variables must be set")
+	}
+
+	client, err := httpclient.New("GERRIT")
+	if err != nil {
+		return nil, err
+	}
+
+	return &gerritForge{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		user:    user,
+		token:   token,
+		client:  client,
+	}, nil
+}
+
+func (f *gerritForge) ListRepos(ctx context.Context, pattern string) ([]Repo, error) {
+	var repos []Repo
+	start := 0
+
+	for {
+		var page map[string]json.RawMessage
+		url := fmt.Sprintf("%s/a/projects/?d&n=%d&S=%d", f.baseURL, gerritPageSize, start)
+		if err := f.getJSON(ctx, url, &page); err != nil {
+			return nil, err
+		}
+
+		for name := range page {
+			if matched, _ := regexp.MatchString(pattern, name); matched {
+				repos = append(repos, Repo{ID: name, Name: name})
+			}
+		}
+
+		if len(page) < gerritPageSize {
+			break
+		}
+		start += len(page)
+	}
+
+	return repos, nil
+}
+
+type gerritChange struct {
+	Insertions  int    `json:"insertions"`
+	Deletions   int    `json:"deletions"`
+	Created     string `json:"created"`
+	MoreChanges bool   `json:"_more_changes"`
+	Owner       struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	} `json:"owner"`
+}
+
+// gerritTimestampLayout is the format Gerrit uses for timestamp fields:
+// UTC, space-separated, nanosecond precision.
+const gerritTimestampLayout = "2006-01-02 15:04:05.000000000"
+
+func (f *gerritForge) CommitStats(ctx context.Context, repo Repo, since string) ([]CommitRecord, error) {
+	query := fmt.Sprintf("project:%s", repo.ID)
+	if since != "" {
+		query += fmt.Sprintf("+after:%s", since)
+	}
+
+	var records []CommitRecord
+	start := 0
+
+	for {
+		url := fmt.Sprintf("%s/a/changes/?q=%s&o=DETAILED_ACCOUNTS&n=%d&S=%d", f.baseURL, query, gerritPageSize, start)
+
+		var changes []gerritChange
+		if err := f.getJSON(ctx, url, &changes); err != nil {
+			return nil, err
+		}
+
+		for _, change := range changes {
+			day := ""
+			if t, err := time.Parse(gerritTimestampLayout, change.Created); err == nil {
+				day = t.Format("2006-01-02")
+			}
+
+			records = append(records, CommitRecord{
+				Author:  identity.RawAuthor{Name: change.Owner.Name, Email: change.Owner.Email},
+				Day:     day,
+				Added:   change.Insertions,
+				Removed: change.Deletions,
+				Total:   change.Insertions + change.Deletions,
+			})
+		}
+
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			break
+		}
+		start += len(changes)
+	}
+
+	return records, nil
+}
+
+// getJSON performs an authenticated GET and decodes the JSON body into out,
+// stripping Gerrit's ")]}'" XSSI-protection prefix line first.
+func (f *gerritForge) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(f.user, f.token)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	body = []byte(strings.TrimPrefix(string(body), gerritXSSIPrefix))
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
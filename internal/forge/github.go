@@ -0,0 +1,182 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/httpclient"
+	"github.com/VielenDanke/gitlab-lines-collector/internal/identity"
+)
+
+// githubForge is the Forge implementation talking to GitHub's REST v3 API.
+type githubForge struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// NewGitHub builds a Forge talking to GitHub, reading GITHUB_URL (defaults
+// to the public API), GITHUB_TOKEN, and GITHUB_MAX_RETRIES/
+// GITHUB_RATE_LIMIT_QPS (see httpclient.New) from the environment.
+func NewGitHub() (Forge, error) {
+	baseURL := os.Getenv("GITHUB_URL")
+	if baseURL == "" {
+		baseURL = "https://api.github.com"
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("GITHUB_TOKEN environment variable must be set")
+	}
+
+	client, err := httpclient.New("GITHUB")
+	if err != nil {
+		return nil, err
+	}
+
+	return &githubForge{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  client,
+	}, nil
+}
+
+type githubRepo struct {
+	FullName string `json:"full_name"`
+}
+
+func (f *githubForge) ListRepos(ctx context.Context, pattern string) ([]Repo, error) {
+	var repos []Repo
+	url := f.baseURL + "/user/repos?per_page=100"
+
+	for url != "" {
+		var page []githubRepo
+		next, err := f.getJSON(ctx, url, &page)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page {
+			if matched, _ := regexp.MatchString(pattern, r.FullName); matched {
+				repos = append(repos, Repo{ID: r.FullName, Name: r.FullName})
+			}
+		}
+
+		url = next
+	}
+
+	return repos, nil
+}
+
+type githubCommit struct {
+	SHA    string `json:"sha"`
+	Commit struct {
+		Author struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+			Date  string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+}
+
+type githubCommitDetail struct {
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+		Total     int `json:"total"`
+	} `json:"stats"`
+}
+
+func (f *githubForge) CommitStats(ctx context.Context, repo Repo, since string) ([]CommitRecord, error) {
+	var records []CommitRecord
+	url := fmt.Sprintf("%s/repos/%s/commits?per_page=100", f.baseURL, repo.ID)
+	if since != "" {
+		url += "&since=" + since + "T00:00:00Z"
+	}
+
+	for url != "" {
+		var commits []githubCommit
+		next, err := f.getJSON(ctx, url, &commits)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, commit := range commits {
+			var detail githubCommitDetail
+			detailURL := fmt.Sprintf("%s/repos/%s/commits/%s", f.baseURL, repo.ID, commit.SHA)
+			if _, err := f.getJSON(ctx, detailURL, &detail); err != nil {
+				return nil, fmt.Errorf("failed to get commit %s: %w", commit.SHA, err)
+			}
+
+			day := ""
+			if t, err := time.Parse(time.RFC3339, commit.Commit.Author.Date); err == nil {
+				day = t.Format("2006-01-02")
+			}
+
+			records = append(records, CommitRecord{
+				Author:  identity.RawAuthor{Name: commit.Commit.Author.Name, Email: commit.Commit.Author.Email},
+				Day:     day,
+				Added:   detail.Stats.Additions,
+				Removed: detail.Stats.Deletions,
+				Total:   detail.Stats.Total,
+			})
+		}
+
+		url = next
+	}
+
+	return records, nil
+}
+
+// getJSON performs an authenticated GET, decodes the JSON body into out and
+// returns the next page URL parsed from the Link header, if any.
+func (f *githubForge) getJSON(ctx context.Context, url string, out interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+f.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("request failed with status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nextPageURL(resp.Header.Get("Link")), nil
+}
+
+// nextPageURL extracts the rel="next" URL from a GitHub Link header.
+func nextPageURL(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		segments := strings.Split(part, ";")
+		if len(segments) != 2 {
+			continue
+		}
+		if strings.TrimSpace(segments[1]) != `rel="next"` {
+			continue
+		}
+		url := strings.TrimSpace(segments[0])
+		return strings.Trim(strings.Trim(url, "<"), ">")
+	}
+	return ""
+}
@@ -0,0 +1,130 @@
+package forge
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/gitlabclient"
+	"github.com/VielenDanke/gitlab-lines-collector/internal/identity"
+	"github.com/xanzy/go-gitlab"
+)
+
+// gitLabForge is the Forge implementation backed by gitlabclient.Client.
+type gitLabForge struct {
+	client *gitlabclient.Client
+}
+
+// NewGitLab builds a Forge talking to GitLab, reading GITLAB_URL and
+// GITLAB_PRIVATE_TOKEN from the environment.
+func NewGitLab() (Forge, error) {
+	gitlabURL := os.Getenv("GITLAB_URL")
+	if gitlabURL == "" {
+		gitlabURL = "https://gitlab.com"
+	}
+	privateToken := os.Getenv("GITLAB_PRIVATE_TOKEN")
+	if privateToken == "" {
+		return nil, fmt.Errorf("GITLAB_PRIVATE_TOKEN environment variable must be set")
+	}
+
+	client, err := gitlabclient.New(gitlabURL, privateToken)
+	if err != nil {
+		return nil, err
+	}
+	return &gitLabForge{client: client}, nil
+}
+
+func (f *gitLabForge) ListRepos(ctx context.Context, pattern string) ([]Repo, error) {
+	opts := &gitlab.ListProjectsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+		Simple:      gitlab.Ptr(true),
+	}
+	var repos []Repo
+
+	for {
+		projects, resp, err := f.client.Projects.ListProjects(opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects: %w", err)
+		}
+
+		for _, project := range projects {
+			if matched, _ := regexp.MatchString(pattern, project.Name+project.PathWithNamespace); matched {
+				repos = append(repos, Repo{ID: fmt.Sprintf("%d", project.ID), Name: project.PathWithNamespace})
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return repos, nil
+}
+
+func (f *gitLabForge) CommitStats(ctx context.Context, repo Repo, since string) ([]CommitRecord, error) {
+	var records []CommitRecord
+
+	opts := &gitlab.ListCommitsOptions{
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	if since != "" {
+		sinceTime, err := time.Parse("2006-01-02", since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse since date: %w", err)
+		}
+		opts.Since = gitlab.Ptr(sinceTime)
+	}
+
+	for {
+		commits, resp, err := f.client.Commits.ListCommits(repo.ID, opts, gitlab.WithContext(ctx))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits: %w", err)
+		}
+
+		for _, commit := range commits {
+			diff, _, err := f.client.Commits.GetCommit(repo.ID, commit.ID, nil, gitlab.WithContext(ctx))
+			if err != nil {
+				return nil, fmt.Errorf("failed to get commit %s: %w", commit.ID, err)
+			}
+			if diff.Stats == nil {
+				continue
+			}
+
+			day := ""
+			if commit.CreatedAt != nil {
+				day = commit.CreatedAt.Format("2006-01-02")
+			}
+
+			records = append(records, CommitRecord{
+				Author:  identity.RawAuthor{Name: commit.AuthorName, Email: commit.AuthorEmail},
+				Day:     day,
+				Added:   diff.Stats.Additions,
+				Removed: diff.Stats.Deletions,
+				Total:   diff.Stats.Total,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return records, nil
+}
+
+// ResolveUsername implements identity.UsernameResolver by searching GitLab
+// users by email.
+func (f *gitLabForge) ResolveUsername(ctx context.Context, email string) (string, bool, error) {
+	users, _, err := f.client.Users.ListUsers(&gitlab.ListUsersOptions{Search: gitlab.Ptr(email)}, gitlab.WithContext(ctx))
+	if err != nil {
+		return "", false, fmt.Errorf("failed to search users: %w", err)
+	}
+	if len(users) == 0 {
+		return "", false, nil
+	}
+	return users[0].Username, true, nil
+}
@@ -0,0 +1,40 @@
+// Package gitlabclient wraps github.com/xanzy/go-gitlab with the retry and
+// backoff behavior this tool needs so callers don't have to think about
+// GitLab's per-token rate limits. Pagination is left to go-gitlab's own
+// ListOptions.
+package gitlabclient
+
+import (
+	"fmt"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/httpclient"
+	"github.com/xanzy/go-gitlab"
+)
+
+// Client is a thin wrapper around *gitlab.Client that bakes in retryable
+// HTTP transport configured from the environment.
+type Client struct {
+	*gitlab.Client
+}
+
+// New builds a Client for gitlabURL/privateToken, honoring GITLAB_MAX_RETRIES
+// and GITLAB_RATE_LIMIT_QPS if set (falling back to sane defaults otherwise).
+func New(gitlabURL, privateToken string) (*Client, error) {
+	httpClient, err := httpclient.New("GITLAB")
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []gitlab.ClientOptionFunc{
+		gitlab.WithHTTPClient(httpClient),
+	}
+	if gitlabURL != "" {
+		opts = append(opts, gitlab.WithBaseURL(gitlabURL))
+	}
+
+	c, err := gitlab.NewClient(privateToken, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build gitlab client: %w", err)
+	}
+	return &Client{Client: c}, nil
+}
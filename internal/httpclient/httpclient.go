@@ -0,0 +1,130 @@
+// Package httpclient builds the retryable, rate-limited and cached HTTP
+// client shared by every forge backend, so GitHub and Gerrit get the same
+// resilience against flaky or rate-limited APIs that GitLab does.
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/cache"
+	"github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultMaxRetries   = 5
+	defaultRateLimitQPS = 10
+	defaultRetryWaitMin = 500 * time.Millisecond
+	defaultRetryWaitMax = 30 * time.Second
+	defaultCacheTTL     = time.Hour
+)
+
+// New builds an *http.Client with retry, QPS rate limiting and (if CACHE_DIR
+// is set) response caching. prefix namesspaces the retry/rate-limit env vars
+// per backend, e.g. prefix "GITLAB" reads GITLAB_MAX_RETRIES and
+// GITLAB_RATE_LIMIT_QPS; CACHE_DIR/CACHE_TTL are shared across backends.
+func New(prefix string) (*http.Client, error) {
+	retryClient := retryablehttp.NewClient()
+	retryClient.RetryMax = envInt(prefix+"_MAX_RETRIES", defaultMaxRetries)
+	retryClient.RetryWaitMin = defaultRetryWaitMin
+	retryClient.RetryWaitMax = defaultRetryWaitMax
+	retryClient.Logger = nil
+	retryClient.CheckRetry = retryablehttp.DefaultRetryPolicy
+	retryClient.Backoff = rateLimitAwareBackoff
+
+	httpClient := retryClient.StandardClient()
+
+	qps := envInt(prefix+"_RATE_LIMIT_QPS", defaultRateLimitQPS)
+	httpClient.Transport = &rateLimitedTransport{
+		base:    httpClient.Transport,
+		limiter: newLimiter(qps),
+	}
+
+	diskCache, err := newCacheFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if diskCache != nil {
+		httpClient.Transport = &cache.Transport{
+			Base:  httpClient.Transport,
+			Cache: diskCache,
+		}
+	}
+
+	return httpClient, nil
+}
+
+// rateLimitAwareBackoff defers to Retry-After when the server sends a 429,
+// and falls back to the library's exponential backoff otherwise.
+func rateLimitAwareBackoff(min, max time.Duration, attempt int, resp *http.Response) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if s := resp.Header.Get("Retry-After"); s != "" {
+			if secs, err := strconv.Atoi(s); err == nil {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	return retryablehttp.DefaultBackoff(min, max, attempt, resp)
+}
+
+// newCacheFromEnv builds the response cache from CACHE_DIR/CACHE_TTL, or
+// returns a nil Cache (caching disabled) when CACHE_DIR isn't set.
+func newCacheFromEnv() (cache.Cache, error) {
+	dir := os.Getenv("CACHE_DIR")
+	if dir == "" {
+		return nil, nil
+	}
+
+	ttl := defaultCacheTTL
+	if s := os.Getenv("CACHE_TTL"); s != "" {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse CACHE_TTL: %w", err)
+		}
+		ttl = parsed
+	}
+
+	return cache.NewDiskCache(dir, ttl)
+}
+
+// rateLimitedTransport caps outbound requests at a fixed QPS so we stay
+// under a backend's per-token rate limit instead of relying solely on 429
+// retries.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+func newLimiter(qps int) *rate.Limiter {
+	if qps <= 0 {
+		qps = defaultRateLimitQPS
+	}
+	return rate.NewLimiter(rate.Limit(qps), qps)
+}
+
+func envInt(name string, fallback int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return fallback
+	}
+	return n
+}
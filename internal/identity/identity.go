@@ -0,0 +1,30 @@
+// Package identity coalesces the many raw (name, email) pairs a commit
+// history accumulates — bots, personal vs. corporate addresses, renamed
+// users — down to a single canonical identity per contributor.
+package identity
+
+import "fmt"
+
+// RawAuthor is the (name, email) pair as reported by a forge's commit API,
+// before any mailmap or username resolution has been applied.
+type RawAuthor struct {
+	Name  string
+	Email string
+}
+
+// Identity is a canonicalized contributor: the mailmap-resolved name/email,
+// plus an optional forge username when one could be resolved.
+type Identity struct {
+	Name     string
+	Email    string
+	Username string
+}
+
+// String renders "Name <email> (@username)", omitting the username
+// parenthetical when it couldn't be resolved.
+func (i Identity) String() string {
+	if i.Username == "" {
+		return fmt.Sprintf("%s <%s>", i.Name, i.Email)
+	}
+	return fmt.Sprintf("%s <%s> (@%s)", i.Name, i.Email, i.Username)
+}
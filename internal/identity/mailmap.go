@@ -0,0 +1,124 @@
+package identity
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// mailmapEmail matches a single `<...>` bracketed email.
+var mailmapEmail = regexp.MustCompile(`<([^>]*)>`)
+
+// Mailmap resolves a raw (name, email) pair to the canonical identity
+// recorded for it, following git's .mailmap conventions.
+type Mailmap struct {
+	byCommitEmail map[string]RawAuthor
+	byProperEmail map[string]string
+}
+
+// LoadMailmapFile parses the file at path, or returns an empty Mailmap if
+// path is empty.
+func LoadMailmapFile(path string) (*Mailmap, error) {
+	if path == "" {
+		return &Mailmap{byCommitEmail: map[string]RawAuthor{}, byProperEmail: map[string]string{}}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mailmap file: %w", err)
+	}
+	defer f.Close()
+
+	return ParseMailmap(f)
+}
+
+// ParseMailmap reads mailmap entries from r, recognizing the two forms:
+//
+//	Proper Name <proper@x> <commit@y>
+//	Proper Name <proper@x>
+//
+// The first rewrites a specific commit email to the proper name+email; the
+// second rewrites any commit from proper@x to that proper name. Other
+// mailmap forms (e.g. the 4-field "Proper Name <proper@x> Commit Name
+// <commit@y>") aren't supported; such lines are skipped rather than
+// guessed at, since a wrong guess would corrupt every commit by that author.
+func ParseMailmap(r io.Reader) (*Mailmap, error) {
+	m := &Mailmap{byCommitEmail: map[string]RawAuthor{}, byProperEmail: map[string]string{}}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		properName, properEmail, commitEmail, ok := parseMailmapLine(line)
+		if !ok {
+			continue
+		}
+
+		if commitEmail != "" {
+			m.byCommitEmail[commitEmail] = RawAuthor{Name: properName, Email: properEmail}
+		} else if properName != "" {
+			m.byProperEmail[properEmail] = properName
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mailmap: %w", err)
+	}
+
+	return m, nil
+}
+
+// parseMailmapLine extracts (properName, properEmail, commitEmail) from a
+// single supported-form mailmap line. ok is false for anything else,
+// including the unsupported 4-field form, so callers drop it instead of
+// misreading the second name as part of the first.
+func parseMailmapLine(line string) (properName, properEmail, commitEmail string, ok bool) {
+	matches := mailmapEmail.FindAllStringSubmatchIndex(line, -1)
+	if len(matches) == 0 || len(matches) > 2 {
+		return "", "", "", false
+	}
+
+	first := matches[0]
+	properName = strings.TrimSpace(line[:first[0]])
+	properEmail = strings.ToLower(strings.TrimSpace(line[first[2]:first[3]]))
+
+	if len(matches) == 1 {
+		if strings.TrimSpace(line[first[1]:]) != "" {
+			return "", "", "", false
+		}
+		return properName, properEmail, "", true
+	}
+
+	second := matches[1]
+	if strings.TrimSpace(line[first[1]:second[0]]) != "" {
+		return "", "", "", false
+	}
+	if strings.TrimSpace(line[second[1]:]) != "" {
+		return "", "", "", false
+	}
+
+	commitEmail = strings.ToLower(strings.TrimSpace(line[second[2]:second[3]]))
+	return properName, properEmail, commitEmail, true
+}
+
+// Canonicalize rewrites raw according to the mailmap, falling back to raw
+// unchanged when no entry matches.
+func (m *Mailmap) Canonicalize(raw RawAuthor) RawAuthor {
+	email := strings.ToLower(raw.Email)
+
+	if proper, ok := m.byCommitEmail[email]; ok {
+		if proper.Name == "" {
+			proper.Name = raw.Name
+		}
+		return proper
+	}
+	if name, ok := m.byProperEmail[email]; ok {
+		return RawAuthor{Name: name, Email: raw.Email}
+	}
+	return raw
+}
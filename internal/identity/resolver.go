@@ -0,0 +1,112 @@
+package identity
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// UsernameResolver looks up the forge username for an email address. It
+// returns ok=false when no matching user could be found.
+type UsernameResolver interface {
+	ResolveUsername(ctx context.Context, email string) (username string, ok bool, err error)
+}
+
+// Resolver canonicalizes raw commit authors: it applies the mailmap, drops
+// anything matching an ignore pattern (bots, noreply addresses, ...), and
+// optionally resolves a forge username, caching one lookup per email.
+type Resolver struct {
+	mailmap   *Mailmap
+	ignore    []*regexp.Regexp
+	usernames UsernameResolver
+
+	mu        sync.Mutex
+	userCache map[string]string
+}
+
+// NewResolver builds a Resolver. usernames may be nil to skip username
+// resolution entirely.
+func NewResolver(mailmap *Mailmap, ignorePatterns []string, usernames UsernameResolver) (*Resolver, error) {
+	ignore := make([]*regexp.Regexp, 0, len(ignorePatterns))
+	for _, pattern := range ignorePatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+		ignore = append(ignore, re)
+	}
+
+	return &Resolver{
+		mailmap:   mailmap,
+		ignore:    ignore,
+		usernames: usernames,
+		userCache: make(map[string]string),
+	}, nil
+}
+
+// IgnorePatternsFromEnv splits IGNORE_AUTHORS_REGEX on commas, e.g.
+// `.*\[bot\]@.*,noreply@`.
+func IgnorePatternsFromEnv() []string {
+	raw := os.Getenv("IGNORE_AUTHORS_REGEX")
+	if raw == "" {
+		return nil
+	}
+
+	var patterns []string
+	for _, p := range strings.Split(raw, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// Resolve canonicalizes raw, returning ignored=true when it matches an
+// ignore pattern and should be dropped from the report entirely.
+func (r *Resolver) Resolve(ctx context.Context, raw RawAuthor) (identity Identity, ignored bool, err error) {
+	for _, re := range r.ignore {
+		if re.MatchString(raw.Email) {
+			return Identity{}, true, nil
+		}
+	}
+
+	canonical := r.mailmap.Canonicalize(raw)
+
+	identity = Identity{Name: canonical.Name, Email: canonical.Email}
+	if r.usernames == nil {
+		return identity, false, nil
+	}
+
+	username, err := r.username(ctx, canonical.Email)
+	if err != nil {
+		return Identity{}, false, err
+	}
+	identity.Username = username
+	return identity, false, nil
+}
+
+func (r *Resolver) username(ctx context.Context, email string) (string, error) {
+	r.mu.Lock()
+	if cached, ok := r.userCache[email]; ok {
+		r.mu.Unlock()
+		return cached, nil
+	}
+	r.mu.Unlock()
+
+	username, ok, err := r.usernames.ResolveUsername(ctx, email)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve username for %s: %w", email, err)
+	}
+	if !ok {
+		username = ""
+	}
+
+	r.mu.Lock()
+	r.userCache[email] = username
+	r.mu.Unlock()
+
+	return username, nil
+}
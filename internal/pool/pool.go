@@ -0,0 +1,152 @@
+// Package pool runs a bounded set of workers over a list of repositories,
+// with per-attempt timeouts, retries on transient failures, and periodic
+// progress reporting.
+package pool
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/forge"
+)
+
+// Result is the outcome of processing one repo: Err is nil on success, or
+// the last error seen after retries were exhausted.
+type Result struct {
+	Repo forge.Repo
+	Err  error
+}
+
+// Pool bounds concurrency, per-attempt timeout and retry behavior for Run.
+type Pool struct {
+	Workers      int
+	Retries      int
+	Timeout      time.Duration
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+}
+
+// New builds a Pool with the given worker count, retry count and per-attempt
+// timeout (0 means no timeout), using sane default backoff bounds. Each
+// attempt at an item, retries included, gets the full timeout.
+func New(workers, retries int, timeout time.Duration) *Pool {
+	return &Pool{
+		Workers:      workers,
+		Retries:      retries,
+		Timeout:      timeout,
+		RetryWaitMin: 500 * time.Millisecond,
+		RetryWaitMax: 10 * time.Second,
+	}
+}
+
+// Process is the per-repo work function. A non-nil error is retried up to
+// Pool.Retries times unless ctx is cancelled.
+type Process func(ctx context.Context, repo forge.Repo) error
+
+// Progress is called after each repo finishes, with the number processed
+// so far and the total repo count.
+type Progress func(processed, total int)
+
+type job struct {
+	index int
+	repo  forge.Repo
+}
+
+// Run processes repos across Pool.Workers goroutines, returning one Result
+// per repo (in repos' original order). It stops dispatching new work, and
+// marks whatever wasn't dispatched as failed with ctx's error, as soon as
+// ctx is cancelled.
+func (p *Pool) Run(ctx context.Context, repos []forge.Repo, process Process, onProgress Progress) []Result {
+	jobs := make(chan job)
+	results := make([]Result, len(repos))
+
+	var processed int64
+	var wg sync.WaitGroup
+
+	for w := 0; w < p.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				err := p.runWithRetry(ctx, j.repo, process)
+				results[j.index] = Result{Repo: j.repo, Err: err}
+
+				done := atomic.AddInt64(&processed, 1)
+				if onProgress != nil {
+					onProgress(int(done), len(repos))
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		for i, repo := range repos {
+			select {
+			case jobs <- job{index: i, repo: repo}:
+			case <-ctx.Done():
+				for ; i < len(repos); i++ {
+					results[i] = Result{Repo: repos[i], Err: ctx.Err()}
+				}
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	return results
+}
+
+func (p *Pool) runWithRetry(ctx context.Context, repo forge.Repo, process Process) error {
+	var lastErr error
+
+	for attempt := 0; attempt <= p.Retries; attempt++ {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if attempt > 0 {
+			wait := p.backoff(attempt)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		attemptCtx := ctx
+		var cancel context.CancelFunc
+		if p.Timeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, p.Timeout)
+		}
+
+		err := process(attemptCtx, repo)
+		if cancel != nil {
+			cancel()
+		}
+		if err == nil {
+			return nil
+		}
+
+		lastErr = err
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", p.Retries+1, lastErr)
+}
+
+// backoff returns a jittered exponential delay for the given attempt
+// (1-indexed), capped at RetryWaitMax.
+func (p *Pool) backoff(attempt int) time.Duration {
+	wait := p.RetryWaitMin * time.Duration(1<<uint(attempt-1))
+	if wait > p.RetryWaitMax {
+		wait = p.RetryWaitMax
+	}
+	jitter := time.Duration(rand.Int63n(int64(wait)/2 + 1))
+	return wait/2 + jitter
+}
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// writeCSV emits one row per (project, author, day) bucket with a header.
+func writeCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"project", "author", "day", "added", "removed", "total", "commits"}); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		record := []string{
+			row.Project,
+			row.Author,
+			row.Day,
+			fmt.Sprintf("%d", row.Added),
+			fmt.Sprintf("%d", row.Removed),
+			fmt.Sprintf("%d", row.Total),
+			fmt.Sprintf("%d", row.Commits),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,14 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// writeJSON emits rows as a JSON array. Callers are expected to have
+// already sorted rows for deterministic output.
+func writeJSON(w io.Writer, rows []Row) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
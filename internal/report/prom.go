@@ -0,0 +1,73 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// promKey identifies a Prometheus series: metrics are labeled by project and
+// author only, so rows for different days collapse into one counter.
+type promKey struct {
+	Project string
+	Author  string
+}
+
+// writeProm emits a Prometheus textfile-collector-compatible file with
+// git_lines_added_total/git_lines_removed_total/git_commits_total counters
+// labeled by project and author.
+func writeProm(w io.Writer, rows []Row) error {
+	added := map[promKey]int{}
+	removed := map[promKey]int{}
+	commits := map[promKey]int{}
+	var order []promKey
+	seen := map[promKey]bool{}
+
+	for _, row := range rows {
+		key := promKey{Project: row.Project, Author: row.Author}
+		if !seen[key] {
+			seen[key] = true
+			order = append(order, key)
+		}
+		added[key] += row.Added
+		removed[key] += row.Removed
+		commits[key] += row.Commits
+	}
+
+	if _, err := fmt.Fprintln(w, "# HELP git_lines_added_total Total lines added per project/author."); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "# TYPE git_lines_added_total counter"); err != nil {
+		return err
+	}
+	for _, key := range order {
+		if _, err := fmt.Fprintf(w, "git_lines_added_total{project=%q,author=%q} %d\n", key.Project, key.Author, added[key]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "# HELP git_lines_removed_total Total lines removed per project/author."); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "# TYPE git_lines_removed_total counter"); err != nil {
+		return err
+	}
+	for _, key := range order {
+		if _, err := fmt.Fprintf(w, "git_lines_removed_total{project=%q,author=%q} %d\n", key.Project, key.Author, removed[key]); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, "# HELP git_commits_total Total commits per project/author."); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "# TYPE git_commits_total counter"); err != nil {
+		return err
+	}
+	for _, key := range order {
+		if _, err := fmt.Fprintf(w, "git_commits_total{project=%q,author=%q} %d\n", key.Project, key.Author, commits[key]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package report renders aggregated commit stats in the output format the
+// caller asked for (text, JSON, CSV or a Prometheus textfile).
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Row is one (project, author, day) bucket. Author is already the
+// mailmap/username-resolved display string, e.g. "Jane Doe <jane@x> (@jane)".
+type Row struct {
+	Project string `json:"project"`
+	Author  string `json:"author"`
+	Day     string `json:"day"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Total   int    `json:"total"`
+	Commits int    `json:"commits"`
+}
+
+// Write renders rows in the given format ("text", "json", "csv" or "prom")
+// to w. Rows are sorted by (project, author, day) first so JSON output
+// diffs deterministically between runs.
+func Write(format string, w io.Writer, rows []Row) error {
+	sorted := make([]Row, len(rows))
+	copy(sorted, rows)
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.Author != b.Author {
+			return a.Author < b.Author
+		}
+		return a.Day < b.Day
+	})
+
+	switch format {
+	case "", "text":
+		return writeText(w, sorted)
+	case "json":
+		return writeJSON(w, sorted)
+	case "csv":
+		return writeCSV(w, sorted)
+	case "prom":
+		return writeProm(w, sorted)
+	default:
+		return fmt.Errorf("unknown output format %q: want text, json, csv or prom", format)
+	}
+}
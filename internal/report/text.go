@@ -0,0 +1,45 @@
+package report
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeText prints the legacy per-author summary: rows collapsed across
+// project and day, one block per author.
+func writeText(w io.Writer, rows []Row) error {
+	totals := map[string]Row{}
+	order := []string{}
+
+	for _, row := range rows {
+		agg, ok := totals[row.Author]
+		if !ok {
+			order = append(order, row.Author)
+		}
+		agg.Author = row.Author
+		agg.Added += row.Added
+		agg.Removed += row.Removed
+		agg.Total += row.Total
+		agg.Commits += row.Commits
+		totals[row.Author] = agg
+	}
+
+	var totalAdded, totalRemoved, total int
+
+	if _, err := fmt.Fprintln(w, "--- Combined Results ---"); err != nil {
+		return err
+	}
+	for _, author := range order {
+		agg := totals[author]
+		if _, err := fmt.Fprintf(w, "Author: %s\nAdded Lines: %d\nRemoved Lines: %d\nTotal Lines: %d\n---\n",
+			agg.Author, agg.Added, agg.Removed, agg.Total); err != nil {
+			return err
+		}
+		totalAdded += agg.Added
+		totalRemoved += agg.Removed
+		total += agg.Total
+	}
+
+	_, err := fmt.Fprintf(w, "Total added: %d\nTotal removed: %d\nTotal: %d\n", totalAdded, totalRemoved, total)
+	return err
+}
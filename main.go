@@ -2,240 +2,226 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"flag"
 	"fmt"
-	"golang.org/x/sync/semaphore"
-	"io"
-	"net/http"
 	"os"
-	"regexp"
+	"os/signal"
 	"strconv"
 	"sync"
+	"syscall"
 	"time"
+
+	"github.com/VielenDanke/gitlab-lines-collector/internal/forge"
+	"github.com/VielenDanke/gitlab-lines-collector/internal/identity"
+	"github.com/VielenDanke/gitlab-lines-collector/internal/pool"
+	"github.com/VielenDanke/gitlab-lines-collector/internal/report"
 )
 
-type Commit struct {
-	ID          string `json:"id"`
-	AuthorEmail string `json:"author_email"`
-}
+const defaultProgressInterval = 5 * time.Second
 
-type DiffStats struct {
-	Additions int `json:"additions"`
-	Deletions int `json:"deletions"`
-	Total     int `json:"total"`
+// rowKey groups commit records into the (project, author, day) buckets the
+// report is built from.
+type rowKey struct {
+	Project string
+	Email   string
+	Day     string
 }
 
-type Project struct {
-	ID                int    `json:"id"`
-	Name              string `json:"name"`
-	PathWithNamespace string `json:"path_with_namespace"`
-}
+func main() {
+	mailmapPath := flag.String("mailmap", os.Getenv("MAILMAP_FILE"), "path to a .mailmap file used to coalesce author identities")
+	outputFormat := flag.String("output", envOr("OUTPUT_FORMAT", "text"), "output format: text, json, csv or prom")
+	outFile := flag.String("out-file", os.Getenv("OUT_FILE"), "file to write the report to (defaults to stdout)")
+	flag.Parse()
 
-func getChangedLines(projectID int, gitlabURL, privateToken, since string) (map[string]map[string]int, error) {
-	url := fmt.Sprintf("%s/api/v4/projects/%d/repository/commits", gitlabURL, projectID)
-	headers := map[string]string{
-		"Authorization": fmt.Sprintf("Bearer %s", privateToken),
-	}
-	allChanges := make(map[string]map[string]int)
-	page := 1
+	sinceDays, err := strconv.Atoi(os.Getenv("SINCE_DAYS"))
+	patternToFind := os.Getenv("PATTERN_TO_FIND")
+	concurrencyNumber := os.Getenv("CONCURRENCY_NUMBER")
 
-	for {
-		params := fmt.Sprintf("?per_page=100&page=%d", page)
-		if since != "" {
-			params += "&since=" + since
-		}
+	if err != nil || sinceDays <= 0 {
+		fmt.Printf("Error parsing SINCE_DAYS: %v. Pick default 360 days\n", err)
+		sinceDays = 360
+	}
+	sinceDate := time.Now().AddDate(0, 0, -sinceDays).Format("2006-01-02")
 
-		resp, err := makeRequest("GET", url+params, headers)
-		if err != nil {
-			return nil, err
-		}
+	concurrencyNumberConverted, convErr := strconv.Atoi(concurrencyNumber)
+	if convErr != nil {
+		fmt.Printf("Failed to convert concurrency number to int: %v. Pick default 20\n", convErr)
+		concurrencyNumberConverted = 20
+	}
 
-		var commits []Commit
-		if err := json.Unmarshal(resp, &commits); err != nil {
-			return nil, fmt.Errorf("failed to parse commits: %w", err)
-		}
-		if len(commits) == 0 {
-			break
-		}
+	projectTimeout := envDuration("PROJECT_TIMEOUT", 0)
+	maxRetries := envInt("PROJECT_MAX_RETRIES", 2)
+	progressInterval := envDuration("PROGRESS_INTERVAL", defaultProgressInterval)
 
-		for _, commit := range commits {
-			commitURL := fmt.Sprintf("%s/api/v4/projects/%d/repository/commits/%s", gitlabURL, projectID, commit.ID)
-			diffResp, err := makeRequest("GET", commitURL, headers)
-			if err != nil {
-				fmt.Printf("Error getting diff for commit %s: %v\n", commit.ID, err)
-				continue
-			}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-			var diffs struct {
-				Stats DiffStats `json:"stats"`
-			}
-			if err := json.Unmarshal(diffResp, &diffs); err != nil {
-				fmt.Printf("Error parsing diff stats for commit %s: %v\n", commit.ID, err)
-				continue
-			}
+	f, err := forge.NewFromEnv()
+	if err != nil {
+		fmt.Printf("Error creating forge client: %v\n", err)
+		os.Exit(1)
+	}
 
-			if _, ok := allChanges[commit.AuthorEmail]; !ok {
-				allChanges[commit.AuthorEmail] = map[string]int{"added": 0, "removed": 0, "total": 0}
-			}
+	mailmap, err := identity.LoadMailmapFile(*mailmapPath)
+	if err != nil {
+		fmt.Printf("Error loading mailmap: %v\n", err)
+		os.Exit(1)
+	}
 
-			allChanges[commit.AuthorEmail]["added"] += diffs.Stats.Additions
-			allChanges[commit.AuthorEmail]["removed"] += diffs.Stats.Deletions
-			allChanges[commit.AuthorEmail]["total"] += diffs.Stats.Total
-		}
-		page++
+	var usernames identity.UsernameResolver
+	if resolver, ok := f.(identity.UsernameResolver); ok {
+		usernames = resolver
 	}
 
-	return allChanges, nil
-}
+	resolver, err := identity.NewResolver(mailmap, identity.IgnorePatternsFromEnv(), usernames)
+	if err != nil {
+		fmt.Printf("Error building identity resolver: %v\n", err)
+		os.Exit(1)
+	}
 
-func getAllProjects(gitlabURL, privateToken, patternToFind string) ([]Project, error) {
-	url := fmt.Sprintf("%s/api/v4/projects", gitlabURL)
-	headers := map[string]string{
-		"Authorization": fmt.Sprintf("Bearer %s", privateToken),
+	allRepos, err := f.ListRepos(ctx, patternToFind)
+	if err != nil {
+		fmt.Printf("Error fetching repos: %v\n", err)
+		os.Exit(1)
 	}
-	var allProjects []Project
 
-	page := 1
+	rows := make(map[rowKey]report.Row)
+	var mu sync.Mutex
+
+	p := pool.New(concurrencyNumberConverted, maxRetries, projectTimeout)
 
-	for {
-		params := fmt.Sprintf("?per_page=100&page=%d&simple=true", page)
-		resp, err := makeRequest("GET", url+params, headers)
+	results := p.Run(ctx, allRepos, func(ctx context.Context, repo forge.Repo) error {
+		records, err := f.CommitStats(ctx, repo, sinceDate)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to get commit stats for %s: %w", repo.Name, err)
 		}
 
-		var projects []Project
+		// Accumulate into a local map and only merge into the shared rows
+		// once every record resolved cleanly, so a retried attempt (see
+		// pool.runWithRetry) starts from scratch instead of double-applying
+		// whatever a prior, partially-failed attempt already merged.
+		local := make(map[rowKey]report.Row)
+		for _, record := range records {
+			id, ignored, err := resolver.Resolve(ctx, record.Author)
+			if err != nil {
+				return fmt.Errorf("failed to resolve identity for %s: %w", record.Author.Email, err)
+			}
+			if ignored {
+				continue
+			}
 
-		if unmErr := json.Unmarshal(resp, &projects); unmErr != nil {
-			return nil, fmt.Errorf("failed to parse projects: %w", unmErr)
+			key := rowKey{Project: repo.Name, Email: id.Email, Day: record.Day}
+			row := local[key]
+			row.Project = repo.Name
+			row.Author = id.String()
+			row.Day = record.Day
+			row.Added += record.Added
+			row.Removed += record.Removed
+			row.Total += record.Total
+			row.Commits++
+			local[key] = row
 		}
-		if len(projects) == 0 {
-			break
+
+		mu.Lock()
+		for key, row := range local {
+			merged := rows[key]
+			merged.Project = row.Project
+			merged.Author = row.Author
+			merged.Day = row.Day
+			merged.Added += row.Added
+			merged.Removed += row.Removed
+			merged.Total += row.Total
+			merged.Commits += row.Commits
+			rows[key] = merged
 		}
-		for _, project := range projects {
-			if matched, _ := regexp.MatchString(patternToFind, project.Name+project.PathWithNamespace); matched {
-				allProjects = append(allProjects, project)
-			}
+		mu.Unlock()
+		return nil
+	}, reportProgress(progressInterval))
+
+	var failed []pool.Result
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
 		}
-		page++
 	}
 
-	return allProjects, nil
-}
-
-func makeRequest(method, url string, headers map[string]string) ([]byte, error) {
-	req, err := http.NewRequest(method, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	reportRows := make([]report.Row, 0, len(rows))
+	for _, row := range rows {
+		reportRows = append(reportRows, row)
 	}
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
 
-	if err != nil {
-		return nil, fmt.Errorf("request failed: %w", err)
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			fmt.Printf("Error closing response body: %v\n", closeErr)
+	out := os.Stdout
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			fmt.Printf("Error creating out-file: %v\n", err)
+			os.Exit(1)
 		}
-	}()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("request failed with status: %d", resp.StatusCode)
+		defer file.Close()
+		out = file
 	}
 
-	return io.ReadAll(resp.Body)
-}
-
-func main() {
-	privateToken := os.Getenv("GITLAB_PRIVATE_TOKEN")
-	sinceDays, err := strconv.Atoi(os.Getenv("SINCE_DAYS"))
-	patternToFind := os.Getenv("PATTERN_TO_FIND")
-	concurrencyNumber := os.Getenv("CONCURRENCY_NUMBER")
-	gitlabURL := os.Getenv("GITLAB_URL")
-
-	if privateToken == "" {
-		fmt.Println("Error: GITLAB_PRIVATE_TOKEN environment variable must be set.")
+	if err := report.Write(*outputFormat, out, reportRows); err != nil {
+		fmt.Printf("Error writing report: %v\n", err)
 		os.Exit(1)
 	}
-	if err != nil || sinceDays <= 0 {
-		fmt.Printf("Error parsing SINCE_DAYS: %v. Pick default 360 days\n", err)
-		sinceDays = 360
-	}
-	sinceDate := time.Now().AddDate(0, 0, -sinceDays).Format("2006-01-02")
 
-	if err != nil {
-		fmt.Printf("Error fetching projects: %v\n", err)
+	if len(failed) > 0 {
+		fmt.Printf("--- %d repo(s) failed ---\n", len(failed))
+		for _, result := range failed {
+			fmt.Printf("%s: %v\n", result.Repo.Name, result.Err)
+		}
 		os.Exit(1)
 	}
-	concurrencyNumberConverted, convErr := strconv.Atoi(concurrencyNumber)
-
-	if convErr != nil {
-		fmt.Printf("Failed to convert concurrency number to int: %v. Pick default 20\n", convErr)
-		concurrencyNumberConverted = 20
-	}
-	if gitlabURL == "" {
-		fmt.Println("Gitlab URL is not set. Using default: https://gitlab.com")
-		gitlabURL = "https://gitlab.com"
-	}
-
-	allProjects, err := getAllProjects(gitlabURL, privateToken, patternToFind)
-
-	var wg sync.WaitGroup
-
-	allChangesCombined := make(map[string]map[string]int)
+}
 
+// reportProgress builds a pool.Progress that prints processed/total at
+// most once per interval, always printing the final 100%.
+func reportProgress(interval time.Duration) pool.Progress {
+	var last time.Time
 	var mu sync.Mutex
 
-	sem := semaphore.NewWeighted(int64(concurrencyNumberConverted))
+	return func(processed, total int) {
+		mu.Lock()
+		defer mu.Unlock()
 
-	for _, project := range allProjects {
-		wg.Add(1)
-		if acqErr := sem.Acquire(context.Background(), 1); acqErr != nil {
-			fmt.Printf("Failed to acquire semaphore: %v\n", acqErr)
-			continue
+		now := time.Now()
+		if processed < total && now.Sub(last) < interval {
+			return
 		}
-		go func(project Project) {
-			defer wg.Done()
-			defer sem.Release(1)
-
-			fmt.Printf("Processing project: %s (ID: %d)\n", project.Name, project.ID)
-			changes, err := getChangedLines(project.ID, gitlabURL, privateToken, sinceDate)
-			if err != nil {
-				fmt.Printf("Skipping project %s due to errors: %v\n", project.Name, err)
-				return
-			}
+		last = now
 
-			mu.Lock()
-			for author, counts := range changes {
-				if _, ok := allChangesCombined[author]; !ok {
-					allChangesCombined[author] = map[string]int{"added": 0, "removed": 0, "total": 0}
-				}
-				allChangesCombined[author]["added"] += counts["added"]
-				allChangesCombined[author]["removed"] += counts["removed"]
-				allChangesCombined[author]["total"] += counts["total"]
-			}
-			mu.Unlock()
-		}(project)
+		fmt.Printf("Progress: %d/%d repos processed\n", processed, total)
 	}
-	fmt.Println("Waiting to finish all calculations")
-
-	wg.Wait()
-
-	var totalAdded, totalRemoved, total int
+}
 
-	fmt.Println("--- Combined Results ---")
+func envOr(name, fallback string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return fallback
+}
 
-	for author, counts := range allChangesCombined {
-		fmt.Printf("Author: %s\nAdded Lines: %d\nRemoved Lines: %d\nTotal Lines: %d\n---\n", author, counts["added"], counts["removed"], counts["total"])
-		totalAdded += counts["added"]
-		totalRemoved += counts["removed"]
-		total += counts["total"]
+func envInt(name string, fallback int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return fallback
 	}
+	return n
+}
 
-	fmt.Printf("Total added: %d\nTotal removed: %d\nTotal: %d\n", totalAdded, totalRemoved, total)
+func envDuration(name string, fallback time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fallback
+	}
+	return d
 }